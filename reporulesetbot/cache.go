@@ -0,0 +1,214 @@
+package reporulesetbot
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/google/go-github/v65/github"
+	"github.com/pkg/errors"
+)
+
+// cacheKind identifies what kind of ID a cacheKey refers to, so that
+// (sourceOrg, targetOrg, id) tuples from different lookup kinds never collide.
+type cacheKind string
+
+const (
+	cacheKindTeam           cacheKind = "team"
+	cacheKindRepo           cacheKind = "repo"
+	cacheKindCustomRepoRole cacheKind = "custom_repo_role"
+	cacheKindInstallation   cacheKind = "installation"
+	cacheKindIntegration    cacheKind = "integration"
+)
+
+// cacheKey identifies a single translated ID lookup.
+type cacheKey struct {
+	sourceOrg string
+	targetOrg string
+	kind      cacheKind
+	id        int64
+}
+
+func (k cacheKey) String() string {
+	return fmt.Sprintf("%s/%s/%s/%d", k.sourceOrg, k.targetOrg, k.kind, k.id)
+}
+
+// Cache stores TTL-backed translations of source-org IDs to target-org IDs,
+// keyed by (sourceOrg, targetOrg, kind, id). Implementations must be safe for
+// concurrent use.
+type Cache interface {
+	// Get returns the cached target ID and true if present and unexpired.
+	Get(key string) (int64, bool)
+	// Set stores id under key with the cache's configured TTL.
+	Set(key string, id int64)
+	// Invalidate removes every cached entry whose key has orgName as either
+	// its sourceOrg or its targetOrg, so a single installation/team/
+	// custom-role webhook can invalidate every cached translation that
+	// depended on that org, regardless of whether it was the source or the
+	// target of the translation.
+	Invalidate(orgName string)
+}
+
+// cacheKeyHasOrg returns true if key (as produced by cacheKey.String) has
+// orgName in its sourceOrg or targetOrg position.
+func cacheKeyHasOrg(key, orgName string) bool {
+	parts := strings.SplitN(key, "/", 4)
+	return len(parts) >= 2 && (parts[0] == orgName || parts[1] == orgName)
+}
+
+// memoryCacheEntry is a single cached value with its expiry.
+type memoryCacheEntry struct {
+	id        int64
+	expiresAt time.Time
+}
+
+// MemoryCache is the default in-memory Cache implementation.
+type MemoryCache struct {
+	ttl     time.Duration
+	mu      sync.RWMutex
+	entries map[string]memoryCacheEntry
+}
+
+// NewMemoryCache creates an in-memory Cache with the given TTL.
+func NewMemoryCache(ttl time.Duration) *MemoryCache {
+	return &MemoryCache{
+		ttl:     ttl,
+		entries: make(map[string]memoryCacheEntry),
+	}
+}
+
+// Get implements Cache.
+func (c *MemoryCache) Get(key string) (int64, bool) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	entry, ok := c.entries[key]
+	if !ok || time.Now().After(entry.expiresAt) {
+		return 0, false
+	}
+	return entry.id, true
+}
+
+// Set implements Cache.
+func (c *MemoryCache) Set(key string, id int64) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.entries[key] = memoryCacheEntry{id: id, expiresAt: time.Now().Add(c.ttl)}
+}
+
+// Invalidate implements Cache.
+func (c *MemoryCache) Invalidate(orgName string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	for key := range c.entries {
+		if cacheKeyHasOrg(key, orgName) {
+			delete(c.entries, key)
+		}
+	}
+}
+
+// HandleInstallationEvent invalidates every cached translation derived from
+// orgName, whether it was the source or the target. Call this on the
+// "installation" webhook event, since a suspend/unsuspend or reinstall can
+// change the installation ID an org resolves to.
+func (h *RulesetHandler) HandleInstallationEvent(orgName string) {
+	h.Cache.Invalidate(orgName)
+}
+
+// HandleTeamEvent invalidates cached team-ID translations involving orgName,
+// whether it was the source or the target org. Call this on the "team"
+// webhook event (rename or deletion), since a stale team slug or ID would
+// otherwise keep resolving to the old team.
+func (h *RulesetHandler) HandleTeamEvent(orgName string) {
+	h.Cache.Invalidate(orgName)
+}
+
+// HandleCustomRepositoryRoleEvent invalidates cached custom-role lookups
+// involving orgName. Call this on the "custom_repository_role" webhook event.
+func (h *RulesetHandler) HandleCustomRepositoryRoleEvent(orgName string) {
+	h.Cache.Invalidate(orgName)
+	h.CustomRepoRolesCache.invalidate(orgName)
+}
+
+// customRepoRolesEntry is a single cached customRepoRolesForOrg response.
+type customRepoRolesEntry struct {
+	roles     *github.OrganizationCustomRepoRoles
+	expiresAt time.Time
+}
+
+// customRepoRolesCache TTL-caches the full list of custom repo roles for an
+// org, since getCustomRepoRolesForOrg pages through the API to build it.
+type customRepoRolesCache struct {
+	ttl     time.Duration
+	mu      sync.RWMutex
+	entries map[string]customRepoRolesEntry
+}
+
+// newCustomRepoRolesCache creates a customRepoRolesCache with the given TTL.
+func newCustomRepoRolesCache(ttl time.Duration) *customRepoRolesCache {
+	return &customRepoRolesCache{ttl: ttl, entries: make(map[string]customRepoRolesEntry)}
+}
+
+func (c *customRepoRolesCache) get(orgName string) (*github.OrganizationCustomRepoRoles, bool) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	entry, ok := c.entries[orgName]
+	if !ok || time.Now().After(entry.expiresAt) {
+		return nil, false
+	}
+	return entry.roles, true
+}
+
+func (c *customRepoRolesCache) set(orgName string, roles *github.OrganizationCustomRepoRoles) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.entries[orgName] = customRepoRolesEntry{roles: roles, expiresAt: time.Now().Add(c.ttl)}
+}
+
+func (c *customRepoRolesCache) invalidate(orgName string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	delete(c.entries, orgName)
+}
+
+// getOrgAppInstallationIDCached wraps getOrgAppInstallationID with the
+// translation Cache so every ruleset file for the same source org doesn't
+// re-resolve the installation ID over the API.
+func (h *RulesetHandler) getOrgAppInstallationIDCached(ctx context.Context, jwtclient *github.Client, orgName string) (int64, error) {
+	key := cacheKey{sourceOrg: orgName, kind: cacheKindInstallation}.String()
+	if cached, ok := h.Cache.Get(key); ok {
+		return cached, nil
+	}
+
+	installation, err := getOrgAppInstallationID(ctx, jwtclient, orgName)
+	if err != nil {
+		return 0, errors.Wrapf(err, "Failed to get installation ID for org %s", orgName)
+	}
+
+	h.Cache.Set(key, installation)
+	return installation, nil
+}
+
+// getCustomRepoRolesForOrgCached wraps getCustomRepoRolesForOrg with a
+// dedicated TTL cache, since every ruleset file for an org re-does the same
+// paginated API calls otherwise.
+func (h *RulesetHandler) getCustomRepoRolesForOrgCached(ctx context.Context, client *github.Client, orgName string) (*github.OrganizationCustomRepoRoles, error) {
+	if cached, ok := h.CustomRepoRolesCache.get(orgName); ok {
+		return cached, nil
+	}
+
+	roles, err := getCustomRepoRolesForOrg(ctx, client, orgName)
+	if err != nil {
+		return nil, err
+	}
+
+	h.CustomRepoRolesCache.set(orgName, roles)
+	return roles, nil
+}
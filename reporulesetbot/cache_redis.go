@@ -0,0 +1,52 @@
+package reporulesetbot
+
+import (
+	"context"
+	"strconv"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// RedisCache is a Cache implementation backed by Redis, for deployments that
+// run multiple bot replicas and need the lookup cache shared across them.
+type RedisCache struct {
+	client *redis.Client
+	ttl    time.Duration
+}
+
+// NewRedisCache creates a Cache backed by the given Redis client.
+func NewRedisCache(client *redis.Client, ttl time.Duration) *RedisCache {
+	return &RedisCache{client: client, ttl: ttl}
+}
+
+// Get implements Cache.
+func (c *RedisCache) Get(key string) (int64, bool) {
+	val, err := c.client.Get(context.Background(), key).Result()
+	if err != nil {
+		return 0, false
+	}
+	id, err := strconv.ParseInt(val, 10, 64)
+	if err != nil {
+		return 0, false
+	}
+	return id, true
+}
+
+// Set implements Cache.
+func (c *RedisCache) Set(key string, id int64) {
+	c.client.Set(context.Background(), key, strconv.FormatInt(id, 10), c.ttl)
+}
+
+// Invalidate implements Cache. It scans for keys with orgName in either the
+// sourceOrg or the targetOrg position of the "sourceOrg/targetOrg/kind/id"
+// key format produced by cacheKey.String.
+func (c *RedisCache) Invalidate(orgName string) {
+	ctx := context.Background()
+	for _, pattern := range []string{orgName + "/*", "*/" + orgName + "/*"} {
+		iter := c.client.Scan(ctx, 0, pattern, 0).Iterator()
+		for iter.Next(ctx) {
+			c.client.Del(ctx, iter.Val())
+		}
+	}
+}
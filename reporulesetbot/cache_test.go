@@ -0,0 +1,47 @@
+package reporulesetbot
+
+import (
+	"testing"
+	"time"
+)
+
+func TestCacheKeyHasOrg(t *testing.T) {
+	tests := []struct {
+		key  string
+		org  string
+		want bool
+	}{
+		{key: cacheKey{sourceOrg: "org-a", targetOrg: "org-b", kind: cacheKindTeam, id: 1}.String(), org: "org-a", want: true},
+		{key: cacheKey{sourceOrg: "org-a", targetOrg: "org-b", kind: cacheKindTeam, id: 1}.String(), org: "org-b", want: true},
+		{key: cacheKey{sourceOrg: "org-a", targetOrg: "org-b", kind: cacheKindTeam, id: 1}.String(), org: "org-c", want: false},
+	}
+
+	for _, tt := range tests {
+		if got := cacheKeyHasOrg(tt.key, tt.org); got != tt.want {
+			t.Errorf("cacheKeyHasOrg(%q, %q) = %v, want %v", tt.key, tt.org, got, tt.want)
+		}
+	}
+}
+
+func TestMemoryCacheInvalidate(t *testing.T) {
+	c := NewMemoryCache(time.Minute)
+	keyAsSource := cacheKey{sourceOrg: "org-a", targetOrg: "org-b", kind: cacheKindTeam, id: 1}.String()
+	keyAsTarget := cacheKey{sourceOrg: "org-b", targetOrg: "org-a", kind: cacheKindRepo, id: 2}.String()
+	keyUnrelated := cacheKey{sourceOrg: "org-c", targetOrg: "org-d", kind: cacheKindRepo, id: 3}.String()
+
+	c.Set(keyAsSource, 10)
+	c.Set(keyAsTarget, 20)
+	c.Set(keyUnrelated, 30)
+
+	c.Invalidate("org-a")
+
+	if _, ok := c.Get(keyAsSource); ok {
+		t.Error("expected entry keyed by org-a as sourceOrg to be invalidated")
+	}
+	if _, ok := c.Get(keyAsTarget); ok {
+		t.Error("expected entry keyed by org-a as targetOrg to be invalidated")
+	}
+	if id, ok := c.Get(keyUnrelated); !ok || id != 30 {
+		t.Error("expected entry unrelated to org-a to survive invalidation")
+	}
+}
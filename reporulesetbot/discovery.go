@@ -0,0 +1,149 @@
+package reporulesetbot
+
+import (
+	"encoding/json"
+	"io/fs"
+	"os"
+	"path/filepath"
+
+	"github.com/bmatcuk/doublestar/v4"
+	"github.com/google/go-github/v65/github"
+	"github.com/pkg/errors"
+	"sigs.k8s.io/yaml"
+)
+
+// DiscoveryConfig configures where ruleset files are discovered from and
+// which of the discovered paths are actually loaded.
+type DiscoveryConfig struct {
+	// Roots is the ordered list of directories to walk recursively looking
+	// for ruleset files. Defaults to defaultDiscoveryRoots if empty.
+	Roots []string
+	// Include is an optional list of glob patterns; if non-empty, a
+	// discovered file must match at least one to be loaded.
+	Include []string
+	// Exclude is an optional list of glob patterns; a discovered file
+	// matching any of these is skipped, even if it matches Include.
+	Exclude []string
+}
+
+// defaultDiscoveryRoots is used when no roots are configured. Like configured
+// roots, every one of these conventional ruleset directories that exists is
+// walked, and their files are combined.
+var defaultDiscoveryRoots = []string{"rulesets", ".github/rulesets", ".gitea/rulesets"}
+
+// rulesetFileExtensions lists the file extensions treated as ruleset files.
+var rulesetFileExtensions = map[string]bool{
+	".json": true,
+	".yml":  true,
+	".yaml": true,
+}
+
+// getRuleSetFiles recursively walks every configured discovery root that
+// exists and returns every ruleset file found across all of them, filtered
+// by the configured include and exclude glob patterns. Roots are additive
+// rather than first-match, so operators can migrate from a flat `rulesets/`
+// layout to per-org subdirectories by adding the new root alongside the old
+// one, without reorganizing existing files.
+func (h *RulesetHandler) getRuleSetFiles() ([]string, error) {
+	roots := h.Discovery.Roots
+	if len(roots) == 0 {
+		roots = defaultDiscoveryRoots
+	}
+
+	var files []string
+	for _, root := range roots {
+		if _, err := os.Stat(root); err != nil {
+			continue
+		}
+
+		err := filepath.WalkDir(root, func(path string, d fs.DirEntry, err error) error {
+			if err != nil {
+				return err
+			}
+			if d.IsDir() {
+				return nil
+			}
+			if !rulesetFileExtensions[filepath.Ext(path)] {
+				return nil
+			}
+			if !h.Discovery.matches(path) {
+				return nil
+			}
+			files = append(files, path)
+			return nil
+		})
+		if err != nil {
+			return nil, errors.Wrapf(err, "Failed to walk ruleset root %s", root)
+		}
+	}
+
+	return files, nil
+}
+
+// rulesetFile is the decoded contents of a ruleset file: the ruleset itself,
+// plus the optional top-level `targets` field that scopes the file to a
+// subset of orgs/installations so it doesn't have to be duplicated per org.
+type rulesetFile struct {
+	Ruleset *github.Ruleset
+	Targets []string
+}
+
+// rulesetFileTargets decodes just the `targets` field, which github.Ruleset
+// doesn't know about.
+type rulesetFileTargets struct {
+	Targets []string `json:"targets,omitempty"`
+}
+
+// unmarshalRulesetFile decodes a ruleset file, choosing JSON or YAML based on
+// the file extension. github.Ruleset only carries JSON struct tags, so YAML
+// files are decoded with sigs.k8s.io/yaml, which converts YAML to JSON before
+// unmarshalling and is therefore compatible with JSON-tagged structs without
+// a dedicated YAML shim type.
+func unmarshalRulesetFile(file string, data []byte) (*rulesetFile, error) {
+	var ruleset *github.Ruleset
+	var targets rulesetFileTargets
+
+	switch filepath.Ext(file) {
+	case ".yml", ".yaml":
+		if err := yaml.Unmarshal(data, &ruleset); err != nil {
+			return nil, errors.Wrap(err, "Failed to unmarshal YAML ruleset")
+		}
+		if err := yaml.Unmarshal(data, &targets); err != nil {
+			return nil, errors.Wrap(err, "Failed to unmarshal YAML ruleset targets")
+		}
+	default:
+		if err := json.Unmarshal(data, &ruleset); err != nil {
+			return nil, errors.Wrap(err, "Failed to unmarshal JSON ruleset")
+		}
+		if err := json.Unmarshal(data, &targets); err != nil {
+			return nil, errors.Wrap(err, "Failed to unmarshal JSON ruleset targets")
+		}
+	}
+
+	return &rulesetFile{Ruleset: ruleset, Targets: targets.Targets}, nil
+}
+
+// matches returns true if path should be loaded under this discovery config:
+// it must match at least one Include pattern (if any are configured) and
+// must not match any Exclude pattern. Patterns use doublestar syntax, so a
+// `**` segment matches across directories (e.g. `archive/**` excludes
+// everything under archive/, not just its immediate children), which plain
+// filepath.Match can't express since it never crosses a path separator.
+func (c DiscoveryConfig) matches(path string) bool {
+	for _, exclude := range c.Exclude {
+		if ok, _ := doublestar.Match(exclude, path); ok {
+			return false
+		}
+	}
+
+	if len(c.Include) == 0 {
+		return true
+	}
+
+	for _, include := range c.Include {
+		if ok, _ := doublestar.Match(include, path); ok {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,89 @@
+package reporulesetbot
+
+import "testing"
+
+func TestDiscoveryConfigMatches(t *testing.T) {
+	tests := []struct {
+		name   string
+		config DiscoveryConfig
+		path   string
+		want   bool
+	}{
+		{
+			name:   "no patterns matches everything",
+			config: DiscoveryConfig{},
+			path:   "rulesets/main.yml",
+			want:   true,
+		},
+		{
+			name:   "include matches single segment",
+			config: DiscoveryConfig{Include: []string{"rulesets/*.yml"}},
+			path:   "rulesets/main.yml",
+			want:   true,
+		},
+		{
+			name:   "include with ** spans directories",
+			config: DiscoveryConfig{Include: []string{"rulesets/**/*.yml"}},
+			path:   "rulesets/org-a/main.yml",
+			want:   true,
+		},
+		{
+			name:   "not matching any include is excluded",
+			config: DiscoveryConfig{Include: []string{"rulesets/*.json"}},
+			path:   "rulesets/main.yml",
+			want:   false,
+		},
+		{
+			name:   "exclude with ** spans directories",
+			config: DiscoveryConfig{Exclude: []string{"archive/**"}},
+			path:   "archive/org-a/old.yml",
+			want:   false,
+		},
+		{
+			name:   "exclude wins over include",
+			config: DiscoveryConfig{Include: []string{"rulesets/**"}, Exclude: []string{"rulesets/**/draft-*.yml"}},
+			path:   "rulesets/org-a/draft-main.yml",
+			want:   false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.config.matches(tt.path); got != tt.want {
+				t.Errorf("matches(%q) = %v, want %v", tt.path, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestUnmarshalRulesetFile(t *testing.T) {
+	yamlData := []byte(`
+name: main
+enforcement: active
+targets:
+  - org-a
+  - org-b
+`)
+	rf, err := unmarshalRulesetFile("main.yaml", yamlData)
+	if err != nil {
+		t.Fatalf("unmarshalRulesetFile(yaml) returned error: %v", err)
+	}
+	if rf.Ruleset.Name != "main" || rf.Ruleset.Enforcement != "active" {
+		t.Errorf("unmarshalRulesetFile(yaml) ruleset = %+v", rf.Ruleset)
+	}
+	if len(rf.Targets) != 2 || rf.Targets[0] != "org-a" || rf.Targets[1] != "org-b" {
+		t.Errorf("unmarshalRulesetFile(yaml) targets = %v", rf.Targets)
+	}
+
+	jsonData := []byte(`{"name": "main", "enforcement": "active", "targets": ["org-a"]}`)
+	rf, err = unmarshalRulesetFile("main.json", jsonData)
+	if err != nil {
+		t.Fatalf("unmarshalRulesetFile(json) returned error: %v", err)
+	}
+	if rf.Ruleset.Name != "main" || rf.Ruleset.Enforcement != "active" {
+		t.Errorf("unmarshalRulesetFile(json) ruleset = %+v", rf.Ruleset)
+	}
+	if len(rf.Targets) != 1 || rf.Targets[0] != "org-a" {
+		t.Errorf("unmarshalRulesetFile(json) targets = %v", rf.Targets)
+	}
+}
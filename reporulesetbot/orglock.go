@@ -0,0 +1,13 @@
+package reporulesetbot
+
+import "sync"
+
+// lockOrg returns the mutex that serializes ruleset mutations for orgName.
+// Both the webhook-triggered apply (ApplyOrPlan) and the reconciler's
+// periodic drift-correction pass hold this for their whole pass over an
+// org's rulesets, so the two can never race against each other. The mutex
+// is not reentrant, so nothing called during such a pass acquires it again.
+func (h *RulesetHandler) lockOrg(orgName string) *sync.Mutex {
+	mu, _ := h.orgLocks.LoadOrStore(orgName, &sync.Mutex{})
+	return mu.(*sync.Mutex)
+}
@@ -0,0 +1,216 @@
+package reporulesetbot
+
+import (
+	"context"
+	"net/http"
+	"os"
+	"strconv"
+
+	"github.com/google/go-github/v65/github"
+	"github.com/pkg/errors"
+	"github.com/rs/zerolog"
+)
+
+// PlanAction describes what applying a ruleset would do.
+type PlanAction string
+
+const (
+	PlanActionCreate PlanAction = "create"
+	PlanActionUpdate PlanAction = "update"
+	PlanActionDelete PlanAction = "delete"
+	PlanActionNoop   PlanAction = "noop"
+)
+
+// PlanEntry is the machine-readable plan for a single ruleset.
+type PlanEntry struct {
+	Name   string     `json:"name"`
+	Action PlanAction `json:"action"`
+	Diff   string     `json:"diff,omitempty"`
+}
+
+// Plan is the full set of actions the bot would take against an org if it
+// applied the current ruleset files for real.
+type Plan struct {
+	Org     string      `json:"org"`
+	Entries []PlanEntry `json:"entries"`
+}
+
+// Plan computes what applying the ruleset files for orgName would do,
+// without mutating anything. It runs getRulesets (and therefore the full
+// bypass-actor and workflow translation pipeline) exactly as processRuleset
+// would, then diffs each result against the live ruleset instead of writing
+// it back, mirroring a `terraform plan`.
+func (h *RulesetHandler) Plan(ctx context.Context, client *github.Client, orgName string, logger zerolog.Logger) (*Plan, error) {
+	desired, liveByName, managedNames, err := h.computePlanInputs(ctx, client, orgName, logger)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Plan{Org: orgName, Entries: computePlanEntries(desired, liveByName, managedNames)}, nil
+}
+
+// computePlanInputs gathers everything computePlanEntries needs: the desired
+// rulesets, the live rulesets keyed by name, and the set of names managed by
+// some ruleset file regardless of that file's `targets`.
+func (h *RulesetHandler) computePlanInputs(ctx context.Context, client *github.Client, orgName string, logger zerolog.Logger) ([]*github.Ruleset, map[string]*github.Ruleset, map[string]bool, error) {
+	desired, err := h.getRulesets(ctx, client, orgName, logger)
+	if err != nil {
+		return nil, nil, nil, errors.Wrap(err, "Failed to compute desired rulesets for plan")
+	}
+
+	liveRulesets, _, err := client.Organizations.GetAllOrganizationRulesets(ctx, orgName)
+	if err != nil {
+		return nil, nil, nil, errors.Wrapf(err, "Failed to list live rulesets for org %s", orgName)
+	}
+
+	liveByName := make(map[string]*github.Ruleset, len(liveRulesets))
+	for _, live := range liveRulesets {
+		liveByName[live.Name] = live
+	}
+
+	managedNames, err := h.managedRulesetNames()
+	if err != nil {
+		return nil, nil, nil, errors.Wrap(err, "Failed to determine managed ruleset names for plan")
+	}
+
+	return desired, liveByName, managedNames, nil
+}
+
+// computePlanEntries is the pure diffing logic behind Plan: given the desired
+// rulesets, the live rulesets keyed by name, and the set of names managed by
+// some ruleset file, it decides create/update/noop for each desired ruleset
+// and delete for any managed ruleset that's live but no longer desired.
+func computePlanEntries(desired []*github.Ruleset, liveByName map[string]*github.Ruleset, managedNames map[string]bool) []PlanEntry {
+	var entries []PlanEntry
+
+	desiredNames := make(map[string]bool, len(desired))
+	for _, ruleset := range desired {
+		desiredNames[ruleset.Name] = true
+
+		live, ok := liveByName[ruleset.Name]
+		if !ok {
+			entries = append(entries, PlanEntry{Name: ruleset.Name, Action: PlanActionCreate})
+			continue
+		}
+
+		if diff := diffRuleset(live, ruleset); diff != "" {
+			entries = append(entries, PlanEntry{Name: ruleset.Name, Action: PlanActionUpdate, Diff: diff})
+		} else {
+			entries = append(entries, PlanEntry{Name: ruleset.Name, Action: PlanActionNoop})
+		}
+	}
+
+	// A managed ruleset that's still live but no longer desired for this org
+	// — because its file was deleted, or its `targets` no longer lists this
+	// org — should be torn down.
+	for name := range liveByName {
+		if desiredNames[name] || !managedNames[name] {
+			continue
+		}
+		entries = append(entries, PlanEntry{Name: name, Action: PlanActionDelete})
+	}
+
+	return entries
+}
+
+// managedRulesetNames returns the set of ruleset names declared across every
+// discovered ruleset file, regardless of that file's `targets`. It's used to
+// tell a ruleset this bot manages (but no longer targets this org) apart from
+// a ruleset some other process or admin created directly in the org.
+func (h *RulesetHandler) managedRulesetNames() (map[string]bool, error) {
+	files, err := h.getRuleSetFiles()
+	if err != nil {
+		return nil, errors.Wrap(err, "Failed to get ruleset files")
+	}
+
+	names := make(map[string]bool, len(files))
+	for _, file := range files {
+		data, err := os.ReadFile(file)
+		if err != nil {
+			return nil, errors.Wrapf(err, "Failed to read ruleset file %s", file)
+		}
+
+		rulesetFile, err := unmarshalRulesetFile(file, data)
+		if err != nil {
+			return nil, errors.Wrapf(err, "Failed to unmarshal ruleset file %s", file)
+		}
+
+		names[rulesetFile.Ruleset.Name] = true
+	}
+	return names, nil
+}
+
+// shouldDryRun returns whether a given webhook request should run in plan
+// mode: the per-request `dry_run` query param overrides the handler's
+// DryRun default so operators can request a plan for a single delivery
+// without restarting the bot.
+func (h *RulesetHandler) shouldDryRun(r *http.Request) bool {
+	if raw := r.URL.Query().Get("dry_run"); raw != "" {
+		if dryRun, err := strconv.ParseBool(raw); err == nil {
+			return dryRun
+		}
+	}
+	return h.DryRun
+}
+
+// ApplyOrPlan is the entrypoint the webhook handler calls once it has the
+// client and org for a ruleset delivery. If the request (or the handler's
+// DryRun default) asks for plan mode, it computes and logs a Plan without
+// mutating anything; otherwise it computes the desired rulesets and applies
+// each one for real, holding orgName's lock for the whole pass so it doesn't
+// race with a concurrent webhook delivery or reconciliation pass for the
+// same org.
+func (h *RulesetHandler) ApplyOrPlan(ctx context.Context, r *http.Request, client *github.Client, orgName string, logger zerolog.Logger) (*Plan, error) {
+	if h.shouldDryRun(r) {
+		plan, err := h.Plan(ctx, client, orgName, logger)
+		if err != nil {
+			return nil, err
+		}
+		plan.LogSummary(logger)
+		return plan, nil
+	}
+
+	mu := h.lockOrg(orgName)
+	mu.Lock()
+	defer mu.Unlock()
+
+	desired, liveByName, managedNames, err := h.computePlanInputs(ctx, client, orgName, logger)
+	if err != nil {
+		return nil, err
+	}
+	entries := computePlanEntries(desired, liveByName, managedNames)
+
+	desiredByName := make(map[string]*github.Ruleset, len(desired))
+	for _, ruleset := range desired {
+		desiredByName[ruleset.Name] = ruleset
+	}
+
+	for _, entry := range entries {
+		if entry.Action == PlanActionDelete {
+			live := liveByName[entry.Name]
+			if _, err := client.Organizations.DeleteOrganizationRuleset(ctx, orgName, live.GetID()); err != nil {
+				return nil, errors.Wrapf(err, "Failed to delete ruleset %s in org %s", entry.Name, orgName)
+			}
+			continue
+		}
+
+		ruleset := desiredByName[entry.Name]
+		liveID := liveByName[entry.Name].GetID()
+		if _, err := applyRuleset(ctx, client, orgName, liveID, ruleset); err != nil {
+			return nil, err
+		}
+	}
+
+	return &Plan{Org: orgName, Entries: entries}, nil
+}
+
+// LogSummary prints a human-readable summary of the plan via zerolog.
+func (p *Plan) LogSummary(logger zerolog.Logger) {
+	for _, entry := range p.Entries {
+		event := logger.Info()
+		if entry.Diff != "" {
+			event = event.Str("diff", entry.Diff)
+		}
+		event.Msgf("[plan] %s: %s", entry.Action, entry.Name)
+	}
+}
@@ -0,0 +1,61 @@
+package reporulesetbot
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/google/go-github/v65/github"
+)
+
+func TestComputePlanEntries(t *testing.T) {
+	desired := []*github.Ruleset{
+		{Name: "missing", Enforcement: "active"},
+		{Name: "drifted", Enforcement: "active"},
+		{Name: "unchanged", Enforcement: "active"},
+	}
+	liveByName := map[string]*github.Ruleset{
+		"drifted":   {Name: "drifted", Enforcement: "disabled"},
+		"unchanged": {Name: "unchanged", Enforcement: "active"},
+		"orphaned":  {Name: "orphaned", Enforcement: "active"},
+		"unmanaged": {Name: "unmanaged", Enforcement: "active"},
+	}
+	managedNames := map[string]bool{
+		"missing":   true,
+		"drifted":   true,
+		"unchanged": true,
+		"orphaned":  true,
+	}
+
+	got := computePlanEntries(desired, liveByName, managedNames)
+
+	byName := make(map[string]PlanEntry, len(got))
+	for _, entry := range got {
+		byName[entry.Name] = entry
+	}
+
+	want := map[string]PlanAction{
+		"missing":   PlanActionCreate,
+		"drifted":   PlanActionUpdate,
+		"unchanged": PlanActionNoop,
+		"orphaned":  PlanActionDelete,
+	}
+	if len(byName) != len(want) {
+		t.Fatalf("computePlanEntries() returned %d entries, want %d: %+v", len(byName), len(want), got)
+	}
+	for name, action := range want {
+		entry, ok := byName[name]
+		if !ok {
+			t.Errorf("missing plan entry for %s", name)
+			continue
+		}
+		if entry.Action != action {
+			t.Errorf("entry for %s: Action = %s, want %s", name, entry.Action, action)
+		}
+	}
+	if _, ok := byName["unmanaged"]; ok {
+		t.Error("unmanaged live ruleset with no desired file should not appear in the plan")
+	}
+	if !reflect.DeepEqual(byName["drifted"].Diff, "enforcement changed; ") {
+		t.Errorf("drifted entry Diff = %q, want %q", byName["drifted"].Diff, "enforcement changed; ")
+	}
+}
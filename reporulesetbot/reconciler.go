@@ -0,0 +1,245 @@
+package reporulesetbot
+
+import (
+	"context"
+	"reflect"
+	"sync"
+	"time"
+
+	"github.com/google/go-github/v65/github"
+	"github.com/pkg/errors"
+	"github.com/rs/zerolog"
+)
+
+// ReconcilerConfig configures the drift-reconciliation loop.
+type ReconcilerConfig struct {
+	// Interval is how often the reconciler walks installations looking for drift.
+	Interval time.Duration
+	// MaxBackoff caps the backoff applied after a failed reconciliation pass.
+	MaxBackoff time.Duration
+}
+
+// Reconciler periodically compares the live org-level rulesets against the
+// desired state declared in the ruleset files, and re-applies the file-defined
+// ruleset whenever it detects drift.
+type Reconciler struct {
+	Handler *RulesetHandler
+	Config  ReconcilerConfig
+	Logger  zerolog.Logger
+
+	liveIDsMu sync.Mutex
+	// liveIDs remembers the last-known live ruleset ID for each
+	// (org, file-declared name), so a manual rename of the live ruleset is
+	// still found (and corrected) on the next pass instead of being treated
+	// as a deletion followed by a duplicate create under the original name.
+	liveIDs map[string]int64
+}
+
+// NewReconciler creates a Reconciler for the given handler.
+func NewReconciler(handler *RulesetHandler, config ReconcilerConfig, logger zerolog.Logger) *Reconciler {
+	if config.Interval <= 0 {
+		config.Interval = 10 * time.Minute
+	}
+	if config.MaxBackoff <= 0 {
+		config.MaxBackoff = 5 * time.Minute
+	}
+	return &Reconciler{
+		Handler: handler,
+		Config:  config,
+		Logger:  logger,
+		liveIDs: make(map[string]int64),
+	}
+}
+
+// Run blocks, walking every installation on Config.Interval until ctx is
+// cancelled. Errors reconciling an individual installation are logged and
+// do not stop the loop or delay later passes. When a whole pass fails
+// (listing installations), the loop backs off before waiting for the next
+// tick, starting at one second and doubling up to Config.MaxBackoff.
+func (r *Reconciler) Run(ctx context.Context) {
+	backoff := time.Duration(0)
+	ticker := time.NewTicker(r.Config.Interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if err := r.reconcileAll(ctx); err != nil {
+				if backoff == 0 {
+					backoff = time.Second
+				} else {
+					backoff *= 2
+				}
+				if backoff > r.Config.MaxBackoff {
+					backoff = r.Config.MaxBackoff
+				}
+				r.Logger.Error().Err(err).Dur("backoff", backoff).Msg("Reconciliation pass failed, backing off.")
+				time.Sleep(backoff)
+				continue
+			}
+			backoff = 0
+		}
+	}
+}
+
+// reconcileAll walks every installation known to the handler and reconciles
+// each org's managed rulesets against the desired state.
+func (r *Reconciler) reconcileAll(ctx context.Context) error {
+	installations, err := r.Handler.ClientCreator.ListInstallations(ctx)
+	if err != nil {
+		return errors.Wrap(err, "Failed to list installations")
+	}
+
+	for _, installation := range installations {
+		orgName := installation.GetAccount().GetLogin()
+		if err := r.reconcileOrg(ctx, orgName, installation); err != nil {
+			r.Logger.Error().Err(err).Msgf("Failed to reconcile org %s.", orgName)
+		}
+	}
+	return nil
+}
+
+// reconcileOrg compares the desired rulesets for orgName against the live
+// rulesets and re-applies any that have drifted or been deleted. It holds
+// orgName's lock for the duration of the pass so it doesn't race with a
+// webhook-triggered ApplyOrPlan pass for the same org.
+func (r *Reconciler) reconcileOrg(ctx context.Context, orgName string, installation *github.Installation) error {
+	mu := r.Handler.lockOrg(orgName)
+	mu.Lock()
+	defer mu.Unlock()
+
+	client, err := r.Handler.ClientCreator.NewInstallationClient(installation.GetID())
+	if err != nil {
+		return errors.Wrap(err, "Failed to create installation client")
+	}
+
+	desired, err := r.Handler.getRulesets(ctx, client, orgName, r.Logger)
+	if err != nil {
+		return errors.Wrap(err, "Failed to compute desired rulesets")
+	}
+
+	liveRulesets, _, err := client.Organizations.GetAllOrganizationRulesets(ctx, orgName)
+	if err != nil {
+		return errors.Wrapf(err, "Failed to list live rulesets for org %s", orgName)
+	}
+
+	liveByName := make(map[string]*github.Ruleset, len(liveRulesets))
+	for _, live := range liveRulesets {
+		liveByName[live.Name] = live
+	}
+
+	for _, ruleset := range desired {
+		live := r.findLive(ctx, client, orgName, ruleset, liveByName)
+
+		if live == nil {
+			r.Logger.Info().Msgf("Ruleset %s missing in org %s, creating.", ruleset.Name, orgName)
+			created, err := applyRuleset(ctx, client, orgName, 0, ruleset)
+			if err != nil {
+				return err
+			}
+			r.rememberLiveID(orgName, ruleset.Name, created.GetID())
+			continue
+		}
+
+		if diff := diffRuleset(live, ruleset); diff != "" {
+			r.Logger.Info().Msgf("Drift detected in ruleset %s (org %s): %s", ruleset.Name, orgName, diff)
+			r.emitDriftEvent(orgName, ruleset, diff)
+			updated, err := applyRuleset(ctx, client, orgName, live.GetID(), ruleset)
+			if err != nil {
+				return err
+			}
+			r.rememberLiveID(orgName, ruleset.Name, updated.GetID())
+			continue
+		}
+		r.rememberLiveID(orgName, ruleset.Name, live.GetID())
+	}
+	return nil
+}
+
+// findLive locates the live ruleset that corresponds to desired. It first
+// tries the ID remembered from a previous pass, which survives a manual
+// rename of the live ruleset (that rename is exactly the drift we want
+// diffRuleset to catch). If no ID is remembered yet, or the remembered
+// ruleset no longer exists, it falls back to matching by desired's current
+// name, which is correct on the very first pass.
+func (r *Reconciler) findLive(ctx context.Context, client *github.Client, orgName string, desired *github.Ruleset, liveByName map[string]*github.Ruleset) *github.Ruleset {
+	if id, ok := r.knownLiveID(orgName, desired.Name); ok {
+		if live, _, err := client.Organizations.GetOrganizationRuleset(ctx, orgName, id); err == nil {
+			return live
+		}
+	}
+	return liveByName[desired.Name]
+}
+
+// knownLiveID returns the live ruleset ID last seen for (orgName, name).
+func (r *Reconciler) knownLiveID(orgName, name string) (int64, bool) {
+	r.liveIDsMu.Lock()
+	defer r.liveIDsMu.Unlock()
+
+	id, ok := r.liveIDs[orgName+"/"+name]
+	return id, ok
+}
+
+// rememberLiveID records the live ruleset ID last seen for (orgName, name),
+// so a future rename of the live ruleset can still be found by ID.
+func (r *Reconciler) rememberLiveID(orgName, name string, id int64) {
+	r.liveIDsMu.Lock()
+	defer r.liveIDsMu.Unlock()
+
+	r.liveIDs[orgName+"/"+name] = id
+}
+
+// applyRuleset creates or updates the file-defined ruleset against the live
+// org, depending on whether liveID (the live ruleset's ID, 0 if it doesn't
+// exist yet) is set, and returns the resulting live ruleset. desired, parsed
+// straight from a ruleset file, never carries a meaningful per-org ID of its
+// own, since chunk0-3's `targets` field lets the same file apply to many
+// orgs — callers must resolve the live ID themselves before calling this.
+func applyRuleset(ctx context.Context, client *github.Client, orgName string, liveID int64, ruleset *github.Ruleset) (*github.Ruleset, error) {
+	if liveID == 0 {
+		created, _, err := client.Organizations.CreateOrganizationRuleset(ctx, orgName, ruleset)
+		if err != nil {
+			return nil, errors.Wrapf(err, "Failed to create ruleset %s in org %s", ruleset.Name, orgName)
+		}
+		return created, nil
+	}
+	updated, _, err := client.Organizations.UpdateOrganizationRuleset(ctx, orgName, liveID, ruleset)
+	if err != nil {
+		return nil, errors.Wrapf(err, "Failed to update ruleset %s in org %s", ruleset.Name, orgName)
+	}
+	return updated, nil
+}
+
+// diffRuleset returns a human-readable description of the drift between the
+// live and desired ruleset, or "" if they match on the fields we manage.
+func diffRuleset(live, desired *github.Ruleset) string {
+	diff := ""
+	if live.Name != desired.Name {
+		diff += "name changed; "
+	}
+	if live.Enforcement != desired.Enforcement {
+		diff += "enforcement changed; "
+	}
+	if !reflect.DeepEqual(live.Rules, desired.Rules) {
+		diff += "rules changed; "
+	}
+	if !reflect.DeepEqual(live.Conditions, desired.Conditions) {
+		diff += "conditions changed; "
+	}
+	if !reflect.DeepEqual(live.BypassActors, desired.BypassActors) {
+		diff += "bypass actors changed; "
+	}
+	return diff
+}
+
+// emitDriftEvent logs a structured audit event describing the detected drift.
+func (r *Reconciler) emitDriftEvent(orgName string, ruleset *github.Ruleset, diff string) {
+	r.Logger.Info().
+		Str("event", "ruleset_drift_detected").
+		Str("org", orgName).
+		Str("ruleset", ruleset.Name).
+		Str("diff", diff).
+		Msg("Reconciler re-applied a drifted ruleset.")
+}
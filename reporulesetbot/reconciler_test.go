@@ -0,0 +1,80 @@
+package reporulesetbot
+
+import (
+	"testing"
+
+	"github.com/google/go-github/v65/github"
+)
+
+func TestDiffRuleset(t *testing.T) {
+	tests := []struct {
+		name string
+		live *github.Ruleset
+		want *github.Ruleset
+		diff string
+	}{
+		{
+			name: "no drift",
+			live: &github.Ruleset{Name: "main", Enforcement: "active"},
+			want: &github.Ruleset{Name: "main", Enforcement: "active"},
+			diff: "",
+		},
+		{
+			name: "name changed",
+			live: &github.Ruleset{Name: "old-name", Enforcement: "active"},
+			want: &github.Ruleset{Name: "new-name", Enforcement: "active"},
+			diff: "name changed; ",
+		},
+		{
+			name: "enforcement changed",
+			live: &github.Ruleset{Name: "main", Enforcement: "disabled"},
+			want: &github.Ruleset{Name: "main", Enforcement: "active"},
+			diff: "enforcement changed; ",
+		},
+		{
+			name: "bypass actors changed",
+			live: &github.Ruleset{
+				Name:        "main",
+				Enforcement: "active",
+				BypassActors: []*github.BypassActor{
+					{ActorID: github.Int64(1)},
+				},
+			},
+			want: &github.Ruleset{
+				Name:        "main",
+				Enforcement: "active",
+				BypassActors: []*github.BypassActor{
+					{ActorID: github.Int64(2)},
+				},
+			},
+			diff: "bypass actors changed; ",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := diffRuleset(tt.live, tt.want); got != tt.diff {
+				t.Errorf("diffRuleset() = %q, want %q", got, tt.diff)
+			}
+		})
+	}
+}
+
+func TestReconcilerKnownLiveID(t *testing.T) {
+	r := &Reconciler{liveIDs: make(map[string]int64)}
+
+	if _, ok := r.knownLiveID("org", "main"); ok {
+		t.Fatal("expected no known live ID before rememberLiveID is called")
+	}
+
+	r.rememberLiveID("org", "main", 42)
+
+	id, ok := r.knownLiveID("org", "main")
+	if !ok || id != 42 {
+		t.Fatalf("knownLiveID() = (%d, %v), want (42, true)", id, ok)
+	}
+
+	if _, ok := r.knownLiveID("other-org", "main"); ok {
+		t.Fatal("expected known live ID to be scoped per org")
+	}
+}
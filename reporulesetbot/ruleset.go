@@ -36,7 +36,7 @@ type Workflow struct {
 func (h *RulesetHandler) getRulesets(ctx context.Context, client *github.Client, orgName string, logger zerolog.Logger) ([]*github.Ruleset, error) {
 	var rulesets []*github.Ruleset
 
-	files, err := getRuleSetFiles()
+	files, err := h.getRuleSetFiles()
 	if err != nil {
 		return nil, errors.Wrapf(err, "Failed to get ruleset files")
 	}
@@ -46,27 +46,39 @@ func (h *RulesetHandler) getRulesets(ctx context.Context, client *github.Client,
 		if err != nil {
 			return nil, err
 		}
+		if ruleset == nil {
+			continue
+		}
 		rulesets = append(rulesets, ruleset)
 	}
 	return rulesets, nil
 }
 
-// processRulesetFile processes the ruleset from a given JSON file.
+// processRulesetFile processes the ruleset from a given JSON or YAML file.
+// If the file declares a `targets` list and orgName isn't in it, the file is
+// skipped and a nil ruleset is returned so callers can omit it from the
+// result without treating it as an error.
 func (h *RulesetHandler) processRulesetFile(file string, ctx context.Context, client *github.Client, orgName string, logger zerolog.Logger) (*github.Ruleset, error) {
 	logger.Info().Msgf("Processing ruleset file %s...", file)
 
-	jsonData, err := os.ReadFile(file)
+	data, err := os.ReadFile(file)
 	if err != nil {
 		logger.Error().Err(err).Msgf("Failed to read ruleset file %s.", file)
 		return nil, errors.Wrap(err, "Failed to read ruleset file")
 	}
 
-	var ruleset *github.Ruleset
-	if err := json.Unmarshal(jsonData, &ruleset); err != nil {
+	rulesetFile, err := unmarshalRulesetFile(file, data)
+	if err != nil {
 		logger.Error().Err(err).Msgf("Failed to unmarshal ruleset file %s.", file)
 		return nil, errors.Wrap(err, "Failed to unmarshal ruleset file")
 	}
 
+	if len(rulesetFile.Targets) > 0 && !containsString(rulesetFile.Targets, orgName) {
+		logger.Info().Msgf("Ruleset file %s does not target org %s, skipping.", file, orgName)
+		return nil, nil
+	}
+
+	ruleset := rulesetFile.Ruleset
 	if err := h.processRuleset(ctx, ruleset, client, orgName, logger); err != nil {
 		return nil, err
 	}
@@ -76,13 +88,25 @@ func (h *RulesetHandler) processRulesetFile(file string, ctx context.Context, cl
 	return ruleset, nil
 }
 
-// processRuleset processes the ruleset.
+// containsString returns true if values contains s.
+func containsString(values []string, s string) bool {
+	for _, value := range values {
+		if value == s {
+			return true
+		}
+	}
+	return false
+}
+
+// processRuleset processes the ruleset. It does not take orgName's lock:
+// the passes that apply its result (ApplyOrPlan and the reconciler) already
+// hold that lock while calling it, and the lock is not reentrant.
 func (h *RulesetHandler) processRuleset(ctx context.Context, ruleset *github.Ruleset, client *github.Client, orgName string, logger zerolog.Logger) error {
 	sourceOrgName := ruleset.Source
 
 	for _, rule := range ruleset.Rules {
 		if rule.Type == "workflows" {
-			if err := processWorkflows(ctx, rule, client, orgName, logger); err != nil {
+			if err := h.processWorkflows(ctx, rule, client, orgName, logger); err != nil {
 				return errors.Wrapf(err, "Failed to process workflows in ruleset file: %s", ruleset.Name)
 			}
 		}
@@ -100,6 +124,12 @@ func (h *RulesetHandler) processRuleset(ctx context.Context, ruleset *github.Rul
 					return errors.Wrapf(err, "Failed to process repository role bypass actor with id %d in ruleset file: %s", bypassActor.GetActorID(), ruleset.Name)
 				}
 			case "Integration":
+				if err := h.processIntegrationActor(ctx, client, bypassActor, sourceOrgName, orgName, logger); err != nil {
+					return errors.Wrapf(err, "Failed to process integration bypass actor with id %d in ruleset file: %s", bypassActor.GetActorID(), ruleset.Name)
+				}
+			case "DeployKey":
+				// Deploy key bypass actors are repository-scoped, not org- or
+				// app-scoped, so there's no cross-org ID to translate.
 				continue
 			default:
 				logger.Warn().Msgf("Unhandled actor type: %s", bypassActor.GetActorType())
@@ -110,7 +140,7 @@ func (h *RulesetHandler) processRuleset(ctx context.Context, ruleset *github.Rul
 }
 
 // processWorkflows processes the workflows in a repository rule.
-func processWorkflows(ctx context.Context, rule *github.RepositoryRule, client *github.Client, orgName string, logger zerolog.Logger) error {
+func (h *RulesetHandler) processWorkflows(ctx context.Context, rule *github.RepositoryRule, client *github.Client, orgName string, logger zerolog.Logger) error {
 	var workflows Workflows
 	if err := json.Unmarshal(*rule.Parameters, &workflows); err != nil {
 		logger.Error().Err(err).Msg("Failed to unmarshal workflow parameters.")
@@ -118,7 +148,7 @@ func processWorkflows(ctx context.Context, rule *github.RepositoryRule, client *
 	}
 
 	for i, workflow := range workflows.Workflows {
-		if err := updateWorkflowRepoID(ctx, &workflow, client, orgName, logger); err != nil {
+		if err := h.updateWorkflowRepoID(ctx, &workflow, client, orgName, logger); err != nil {
 			return err
 		}
 		workflows.Workflows[i] = workflow
@@ -135,25 +165,57 @@ func processWorkflows(ctx context.Context, rule *github.RepositoryRule, client *
 }
 
 // updateWorkflowRepoID updates the repository ID in a workflow.
-func updateWorkflowRepoID(ctx context.Context, workflow *Workflow, client *github.Client, orgName string, logger zerolog.Logger) error {
+func (h *RulesetHandler) updateWorkflowRepoID(ctx context.Context, workflow *Workflow, client *github.Client, orgName string, logger zerolog.Logger) error {
 	repoName, err := getRepoName(ctx, client, workflow.RepositoryID)
 	if err != nil {
 		logger.Error().Err(err).Msg("Failed to get repository name")
 		return errors.Wrapf(err, "Failed to get repository name for repository ID %d", workflow.RepositoryID)
 	}
 
-	newRepoID, err := getRepoID(ctx, client, orgName, repoName)
-	if err != nil {
-		logger.Error().Err(err).Msg("Failed to get repository ID.")
-		return errors.Wrapf(err, "Failed to get repository ID for repository %s/%s", orgName, repoName)
+	// Only the repo-ID translation is cached. Whether the workflow file
+	// still exists at its ref is re-checked on every call, even on a cache
+	// hit, since the file can be deleted or moved in the target repo after
+	// the translation was first cached.
+	key := cacheKey{sourceOrg: orgName, targetOrg: orgName, kind: cacheKindRepo, id: workflow.RepositoryID}.String()
+	newRepoID, ok := h.Cache.Get(key)
+	if !ok {
+		newRepoID, err = getRepoID(ctx, client, orgName, repoName)
+		if err != nil {
+			logger.Error().Err(err).Msg("Failed to get repository ID.")
+			return errors.Wrapf(err, "Failed to get repository ID for repository %s/%s", orgName, repoName)
+		}
+		h.Cache.Set(key, newRepoID)
+	}
+
+	if err := validateWorkflowRef(ctx, client, orgName, repoName, workflow.Path, workflow.Ref); err != nil {
+		logger.Error().Err(err).Msgf("Workflow %s does not exist at ref %s in %s/%s.", workflow.Path, workflow.Ref, orgName, repoName)
+		return err
 	}
 
 	workflow.RepositoryID = newRepoID
 	return nil
 }
 
+// validateWorkflowRef checks that path exists at ref in the given repo, so a
+// ruleset doesn't silently reference a workflow file that's missing in the
+// target repo and end up blocking every PR there.
+func validateWorkflowRef(ctx context.Context, client *github.Client, orgName, repoName, path, ref string) error {
+	_, _, _, err := client.Repositories.GetContents(ctx, orgName, repoName, path, &github.RepositoryContentGetOptions{Ref: ref})
+	if err != nil {
+		return errors.Wrapf(err, "Workflow file %s does not exist at ref %s in %s/%s", path, ref, orgName, repoName)
+	}
+	return nil
+}
+
 // processTeamActor processes a team actor.
 func (h *RulesetHandler) processTeamActor(ctx context.Context, client *github.Client, actor *github.BypassActor, sourceOrgName, orgName string) error {
+	teamID := actor.GetActorID()
+
+	key := cacheKey{sourceOrg: sourceOrgName, targetOrg: orgName, kind: cacheKindTeam, id: teamID}.String()
+	if cached, ok := h.Cache.Get(key); ok {
+		actor.ActorID = &cached
+		return nil
+	}
 
 	// create jwt client
 	jwtclient, err := newJWTClient()
@@ -162,7 +224,7 @@ func (h *RulesetHandler) processTeamActor(ctx context.Context, client *github.Cl
 	}
 
 	// get installation for the app
-	installation, err := getOrgAppInstallationID(ctx, jwtclient, sourceOrgName)
+	installation, err := h.getOrgAppInstallationIDCached(ctx, jwtclient, sourceOrgName)
 	if err != nil {
 		return errors.Wrap(err, "Failed to get installation for the app")
 	}
@@ -179,8 +241,6 @@ func (h *RulesetHandler) processTeamActor(ctx context.Context, client *github.Cl
 		return errors.Wrap(err, "Failed to get org ID")
 	}
 
-	teamID := actor.GetActorID()
-
 	sourceTeam, err := getTeamByID(ctx, sourceClient, orgID, teamID)
 	if err != nil {
 		errors.Wrapf(err, "Failed to get team with ID %d", teamID)
@@ -198,6 +258,7 @@ func (h *RulesetHandler) processTeamActor(ctx context.Context, client *github.Cl
 	teamID = newTeam.GetID()
 
 	actor.ActorID = &teamID
+	h.Cache.Set(key, teamID)
 
 	return nil
 }
@@ -206,6 +267,12 @@ func (h *RulesetHandler) processTeamActor(ctx context.Context, client *github.Cl
 func (h *RulesetHandler) processRepoRoleActor(ctx context.Context, client *github.Client, actor *github.BypassActor, sourceOrgName, orgName string) error {
 	actorID := actor.GetActorID()
 
+	key := cacheKey{sourceOrg: sourceOrgName, targetOrg: orgName, kind: cacheKindCustomRepoRole, id: actorID}.String()
+	if cached, ok := h.Cache.Get(key); ok {
+		actor.ActorID = &cached
+		return nil
+	}
+
 	// create jwt client
 	jwtclient, err := newJWTClient()
 	if err != nil {
@@ -213,7 +280,7 @@ func (h *RulesetHandler) processRepoRoleActor(ctx context.Context, client *githu
 	}
 
 	// get installation for the app
-	installation, err := getOrgAppInstallationID(ctx, jwtclient, sourceOrgName)
+	installation, err := h.getOrgAppInstallationIDCached(ctx, jwtclient, sourceOrgName)
 	if err != nil {
 		return errors.Wrap(err, "Failed to get installation for the app")
 	}
@@ -225,7 +292,7 @@ func (h *RulesetHandler) processRepoRoleActor(ctx context.Context, client *githu
 	}
 
 	// get custom repo roles for the source org
-	customRepoRoles, err := getCustomRepoRolesForOrg(ctx, sourceClient, sourceOrgName)
+	customRepoRoles, err := h.getCustomRepoRolesForOrgCached(ctx, sourceClient, sourceOrgName)
 	if err != nil {
 		return errors.Wrap(err, "Failed to get custom repo roles for source org")
 	}
@@ -239,7 +306,7 @@ func (h *RulesetHandler) processRepoRoleActor(ctx context.Context, client *githu
 	}
 
 	// get custom repo roles for the target org
-	customRepoRoles, err = getCustomRepoRolesForOrg(ctx, client, orgName)
+	customRepoRoles, err = h.getCustomRepoRolesForOrgCached(ctx, client, orgName)
 	if err != nil {
 		return errors.Wrap(err, "Failed to get custom repo roles for target org")
 	}
@@ -248,10 +315,56 @@ func (h *RulesetHandler) processRepoRoleActor(ctx context.Context, client *githu
 		if repoRole.GetName() == roleName {
 			actorID = repoRole.GetID()
 			actor.ActorID = &actorID
+			h.Cache.Set(key, actorID)
+			return nil
+		}
+	}
+
+	return nil
+}
+
+// processIntegrationActor processes an Integration (GitHub App) bypass actor.
+// It resolves the source app's slug from its installation in the source org,
+// then rewrites ActorID to that same app's installation ID in the target
+// org. This is required for release-automation and similar apps that are
+// granted ruleset bypass directly, which would otherwise silently lose that
+// grant when the ruleset is copied across orgs.
+func (h *RulesetHandler) processIntegrationActor(ctx context.Context, client *github.Client, actor *github.BypassActor, sourceOrgName, orgName string, logger zerolog.Logger) error {
+	installationID := actor.GetActorID()
+
+	key := cacheKey{sourceOrg: sourceOrgName, targetOrg: orgName, kind: cacheKindIntegration, id: installationID}.String()
+	if cached, ok := h.Cache.Get(key); ok {
+		actor.ActorID = &cached
+		return nil
+	}
+
+	// create jwt client
+	jwtclient, err := newJWTClient()
+	if err != nil {
+		return errors.Wrap(err, "Failed to create JWT client")
+	}
+
+	sourceInstallation, _, err := jwtclient.Apps.GetInstallation(ctx, installationID)
+	if err != nil {
+		return errors.Wrapf(err, "Failed to get installation with ID %d", installationID)
+	}
+	appSlug := sourceInstallation.GetAppSlug()
+
+	targetInstallations, _, err := client.Organizations.ListInstallations(ctx, orgName, nil)
+	if err != nil {
+		return errors.Wrapf(err, "Failed to list app installations for org %s", orgName)
+	}
+
+	for _, installation := range targetInstallations.Installations {
+		if installation.GetAppSlug() == appSlug {
+			targetID := installation.GetID()
+			actor.ActorID = &targetID
+			h.Cache.Set(key, targetID)
 			return nil
 		}
 	}
 
+	logger.Warn().Msgf("App %s is not installed in org %s, skipping integration bypass actor.", appSlug, orgName)
 	return nil
 }
 